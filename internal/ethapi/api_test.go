@@ -1139,6 +1139,192 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestCallBundle(t *testing.T) {
+	t.Parallel()
+	// Initialize test accounts
+	var (
+		accounts = newAccounts(3)
+		coinbase = common.Address{0xc0, 0xff, 0xee}
+		genesis  = &core.Genesis{
+			Config: params.MergedTestChainConfig,
+			Alloc: core.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+				accounts[2].addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		genBlocks = 10
+		signer    = types.LatestSigner(params.MergedTestChainConfig)
+		baseFee   = big.NewInt(params.GWei)
+	)
+	api := NewBundleAPI(newTestBackend(t, genBlocks, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	}))
+	signTx := func(key *ecdsa.PrivateKey, tx types.TxData) hexutil.Bytes {
+		enc, err := types.MustSignNewTx(key, signer, tx).MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to encode tx: %v", err)
+		}
+		return enc
+	}
+	txs := []hexutil.Bytes{
+		// Plain transfer paying a 2 gwei tip
+		signTx(accounts[0].key, &types.DynamicFeeTx{Nonce: 0, To: &accounts[1].addr, Value: big.NewInt(1000), Gas: params.TxGas, GasTipCap: big.NewInt(2 * params.GWei), GasFeeCap: big.NewInt(10 * params.GWei)}),
+		// Direct payment to the coinbase without a tip
+		signTx(accounts[1].key, &types.DynamicFeeTx{Nonce: 0, To: &coinbase, Value: big.NewInt(1e15), Gas: params.TxGas, GasTipCap: common.Big0, GasFeeCap: big.NewInt(10 * params.GWei)}),
+		// Contract creation whose init code reverts
+		signTx(accounts[2].key, &types.DynamicFeeTx{Nonce: 0, Value: common.Big0, Gas: 100000, GasTipCap: big.NewInt(params.GWei), GasFeeCap: big.NewInt(10 * params.GWei), Data: common.FromHex("60006000fd")}),
+	}
+	res, err := api.CallBundle(context.Background(), CallBundleArgs{
+		Txs:      txs,
+		Coinbase: &coinbase,
+		BaseFee:  (*hexutil.Big)(baseFee),
+	})
+	if err != nil {
+		t.Fatalf("failed to call bundle: %v", err)
+	}
+	if res.StateBlockNumber != uint64(genBlocks) {
+		t.Errorf("state block mismatch, have %d, want %d", res.StateBlockNumber, genBlocks)
+	}
+	if len(res.Results) != len(txs) {
+		t.Fatalf("result count mismatch, have %d, want %d", len(res.Results), len(txs))
+	}
+	var (
+		transfer = res.Results[0]
+		payment  = res.Results[1]
+		reverted = res.Results[2]
+	)
+	if transfer.Error != "" || transfer.Value == nil || transfer.GasUsed != params.TxGas {
+		t.Errorf("transfer result mismatch: %+v", transfer)
+	}
+	if want := new(big.Int).Mul(big.NewInt(int64(params.TxGas)), big.NewInt(2*params.GWei)).String(); transfer.GasFees != want || transfer.CoinbaseDiff != want {
+		t.Errorf("transfer fees mismatch, have gasFees %s coinbaseDiff %s, want %s", transfer.GasFees, transfer.CoinbaseDiff, want)
+	}
+	if payment.GasFees != "0" || payment.EthSentToCoinbase != big.NewInt(1e15).String() {
+		t.Errorf("payment result mismatch: %+v", payment)
+	}
+	// Gas prices are the coinbase diff per unit of gas, including direct payments
+	if want := big.NewInt(2 * params.GWei).String(); transfer.GasPrice != want {
+		t.Errorf("transfer gas price mismatch, have %s, want %s", transfer.GasPrice, want)
+	}
+	if want := new(big.Int).Div(big.NewInt(1e15), big.NewInt(int64(params.TxGas))).String(); payment.GasPrice != want {
+		t.Errorf("payment gas price mismatch, have %s, want %s", payment.GasPrice, want)
+	}
+	if reverted.Error != vm.ErrExecutionReverted.Error() || reverted.Value != nil || reverted.ToAddress != nil {
+		t.Errorf("reverted result mismatch: %+v", reverted)
+	}
+	total := transfer.GasUsed + payment.GasUsed + reverted.GasUsed
+	if res.TotalGasUsed != total {
+		t.Errorf("total gas mismatch, have %d, want %d", res.TotalGasUsed, total)
+	}
+	if res.EthSentToCoinbase != big.NewInt(1e15).String() {
+		t.Errorf("bundle coinbase transfers mismatch, have %s, want %d", res.EthSentToCoinbase, int64(1e15))
+	}
+	diff, _ := new(big.Int).SetString(res.CoinbaseDiff, 10)
+	if want := new(big.Int).Div(diff, new(big.Int).SetUint64(total)).String(); res.BundleGasPrice != want {
+		t.Errorf("bundle gas price mismatch, have %s, want %s", res.BundleGasPrice, want)
+	}
+	var hashes []byte
+	for _, enc := range txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(enc); err != nil {
+			t.Fatalf("failed to decode tx: %v", err)
+		}
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	if want := crypto.Keccak256Hash(hashes); res.BundleHash != want {
+		t.Errorf("bundle hash mismatch, have %v, want %v", res.BundleHash, want)
+	}
+	// Contract creations returning a block context value as their code
+	returnOp := func(key *ecdsa.PrivateKey, op vm.OpCode) hexutil.Bytes {
+		code := append([]byte{byte(op)}, common.FromHex("60005260206000f3")...)
+		return signTx(key, &types.DynamicFeeTx{Nonce: 0, Value: common.Big0, Gas: 100000, GasTipCap: common.Big0, GasFeeCap: big.NewInt(10 * params.GWei), Data: code})
+	}
+	returnValue := func(t *testing.T, res CallBundleTxResult) *big.Int {
+		t.Helper()
+		if res.Error != "" || res.Value == nil {
+			t.Fatalf("tx %v failed: %+v", res.TxHash, res)
+		}
+		return new(big.Int).SetBytes(*res.Value)
+	}
+	// Simulation on top of an older state block
+	older := rpc.BlockNumberOrHashWithNumber(5)
+	res, err = api.CallBundle(context.Background(), CallBundleArgs{
+		Txs:                    []hexutil.Bytes{returnOp(accounts[0].key, vm.NUMBER)},
+		StateBlockNumberOrHash: &older,
+	})
+	if err != nil {
+		t.Fatalf("failed to call bundle on older state: %v", err)
+	}
+	if res.StateBlockNumber != 5 {
+		t.Errorf("state block mismatch, have %d, want %d", res.StateBlockNumber, 5)
+	}
+	if have := returnValue(t, res.Results[0]); have.Uint64() != 6 {
+		t.Errorf("block number mismatch, have %d, want %d", have, 6)
+	}
+	// Block number and timestamp overrides are visible to the EVM, and
+	// BLOBBASEFEE is available on Cancun chains
+	var (
+		blockNumber = rpc.BlockNumber(100)
+		timestamp   = uint64(12345)
+	)
+	res, err = api.CallBundle(context.Background(), CallBundleArgs{
+		Txs: []hexutil.Bytes{
+			returnOp(accounts[0].key, vm.NUMBER),
+			returnOp(accounts[1].key, vm.TIMESTAMP),
+			returnOp(accounts[2].key, vm.BLOBBASEFEE),
+		},
+		BlockNumber: blockNumber,
+		Timestamp:   &timestamp,
+	})
+	if err != nil {
+		t.Fatalf("failed to call bundle with overrides: %v", err)
+	}
+	if have := returnValue(t, res.Results[0]); have.Int64() != blockNumber.Int64() {
+		t.Errorf("block number mismatch, have %d, want %d", have, blockNumber)
+	}
+	if have := returnValue(t, res.Results[1]); have.Uint64() != timestamp {
+		t.Errorf("timestamp mismatch, have %d, want %d", have, timestamp)
+	}
+	if have := returnValue(t, res.Results[2]); have.Uint64() != params.BlobTxMinBlobGasprice {
+		t.Errorf("blob base fee mismatch, have %d, want %d", have, params.BlobTxMinBlobGasprice)
+	}
+	// Out of gas without revert data
+	loop := signTx(accounts[0].key, &types.DynamicFeeTx{Nonce: 0, Value: common.Big0, Gas: 60000, GasTipCap: common.Big0, GasFeeCap: big.NewInt(10 * params.GWei), Data: common.FromHex("5b600056")})
+	res, err = api.CallBundle(context.Background(), CallBundleArgs{Txs: []hexutil.Bytes{loop}})
+	if err != nil {
+		t.Fatalf("failed to call bundle: %v", err)
+	}
+	if oog := res.Results[0]; oog.Error != vm.ErrOutOfGas.Error() || oog.Revert != "" || oog.Value != nil || oog.GasUsed != 60000 {
+		t.Errorf("out of gas result mismatch: %+v", oog)
+	}
+	// Block gas limits above the RPC gas cap stay visible to GASLIMIT, but
+	// the bundle can't spend more gas than the cap
+	var (
+		gasCap  = api.b.RPCGasCap()
+		overCap = 2 * gasCap
+	)
+	res, err = api.CallBundle(context.Background(), CallBundleArgs{Txs: []hexutil.Bytes{returnOp(accounts[0].key, vm.GASLIMIT)}, GasLimit: &overCap})
+	if err != nil {
+		t.Fatalf("failed to call bundle with gas limit override: %v", err)
+	}
+	if have := returnValue(t, res.Results[0]); have.Uint64() != overCap {
+		t.Errorf("gas limit mismatch, have %d, want %d", have, overCap)
+	}
+	greedy := signTx(accounts[0].key, &types.DynamicFeeTx{Nonce: 0, To: &accounts[1].addr, Value: common.Big0, Gas: gasCap + 1, GasTipCap: common.Big0, GasFeeCap: big.NewInt(10 * params.GWei)})
+	if _, err := api.CallBundle(context.Background(), CallBundleArgs{Txs: []hexutil.Bytes{greedy}, GasLimit: &overCap}); !errors.Is(err, core.ErrGasLimitReached) {
+		t.Errorf("tx gas above RPC gas cap error mismatch, have %v, want %v", err, core.ErrGasLimitReached)
+	}
+	// Invalid bundles must be rejected before execution
+	if _, err := api.CallBundle(context.Background(), CallBundleArgs{}); err == nil {
+		t.Errorf("expected error for empty bundle")
+	}
+	deposit, _ := types.NewTx(&types.DepositTx{From: accounts[0].addr, To: &accounts[1].addr, Gas: params.TxGas}).MarshalBinary()
+	if _, err := api.CallBundle(context.Background(), CallBundleArgs{Txs: []hexutil.Bytes{deposit}}); !errors.Is(err, types.ErrTxTypeNotSupported) {
+		t.Errorf("deposit tx error mismatch, have %v, want %v", err, types.ErrTxTypeNotSupported)
+	}
+}
+
 func TestSignTransaction(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -0,0 +1,264 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI offers an API for simulating transaction bundles.
+type BundleAPI struct {
+	b Backend
+}
+
+// NewBundleAPI creates a new bundle API instance.
+func NewBundleAPI(b Backend) *BundleAPI {
+	return &BundleAPI{b}
+}
+
+// CallBundleArgs represents the arguments for eth_callBundle, following the
+// Flashbots request schema.
+type CallBundleArgs struct {
+	Txs                    []hexutil.Bytes        `json:"txs"`
+	BlockNumber            rpc.BlockNumber        `json:"blockNumber"`
+	StateBlockNumberOrHash *rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Coinbase               *common.Address        `json:"coinbase"`
+	Timestamp              *uint64                `json:"timestamp"`
+	GasLimit               *uint64                `json:"gasLimit"`
+	BaseFee                *hexutil.Big           `json:"baseFee"`
+}
+
+// CallBundleTxResult is the outcome of a single bundle transaction. Wei amounts
+// are encoded as decimal strings, matching the Flashbots response format.
+type CallBundleTxResult struct {
+	TxHash            common.Hash     `json:"txHash"`
+	GasUsed           uint64          `json:"gasUsed"`
+	FromAddress       common.Address  `json:"fromAddress"`
+	ToAddress         *common.Address `json:"toAddress"`
+	GasPrice          string          `json:"gasPrice"`
+	GasFees           string          `json:"gasFees"`
+	CoinbaseDiff      string          `json:"coinbaseDiff"`
+	EthSentToCoinbase string          `json:"ethSentToCoinbase"`
+	Value             *hexutil.Bytes  `json:"value,omitempty"`
+	Error             string          `json:"error,omitempty"`
+	Revert            string          `json:"revert,omitempty"`
+}
+
+// CallBundleResult is the aggregated outcome of eth_callBundle.
+type CallBundleResult struct {
+	BundleHash        common.Hash          `json:"bundleHash"`
+	BundleGasPrice    string               `json:"bundleGasPrice"`
+	CoinbaseDiff      string               `json:"coinbaseDiff"`
+	EthSentToCoinbase string               `json:"ethSentToCoinbase"`
+	GasFees           string               `json:"gasFees"`
+	TotalGasUsed      uint64               `json:"totalGasUsed"`
+	StateBlockNumber  uint64               `json:"stateBlockNumber"`
+	Results           []CallBundleTxResult `json:"results"`
+}
+
+// CallBundle executes the given signed transactions in order on top of the state
+// of the requested block (latest if omitted), as if they were included in the
+// block with number args.BlockNumber. The state is discarded afterwards.
+//
+// Reverting transactions do not abort the simulation; their outcome is reported
+// in the per-transaction result instead.
+func (s *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	if len(args.Txs) == 0 {
+		return nil, errors.New("bundle missing txs")
+	}
+	defer func(start time.Time) { log.Debug("Executing EVM call bundle finished", "runtime", time.Since(start)) }(time.Now())
+
+	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if args.StateBlockNumberOrHash != nil {
+		blockNrOrHash = *args.StateBlockNumberOrHash
+	}
+	state, parent, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	txs := make([]*types.Transaction, 0, len(args.Txs))
+	for i, encoded := range args.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encoded); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+		if tx.Type() == types.DepositTxType || tx.Type() == types.BlobTxType {
+			return nil, fmt.Errorf("tx %d: %w", i, types.ErrTxTypeNotSupported)
+		}
+		txs = append(txs, tx)
+	}
+	// Assemble the header of the block the bundle is simulated in, defaulting
+	// to the child of the state block.
+	var (
+		config = s.b.ChainConfig()
+		header = &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+			Difficulty: parent.Difficulty,
+			MixDigest:  parent.MixDigest,
+			Coinbase:   parent.Coinbase,
+		}
+	)
+	if args.BlockNumber > 0 {
+		header.Number = big.NewInt(args.BlockNumber.Int64())
+	}
+	if args.Timestamp != nil {
+		header.Time = *args.Timestamp
+	}
+	if args.GasLimit != nil {
+		header.GasLimit = *args.GasLimit
+	}
+	// Cap the gas available to the whole bundle by the RPC gas cap, same as
+	// for eth_call, so that callers can't request unmetered execution. Block
+	// gas limits above the cap, whether inherited or overridden, are clamped
+	// for the bundle budget only; the header keeps them so GASLIMIT is exact.
+	gasBudget := header.GasLimit
+	if gasCap := s.b.RPCGasCap(); gasCap != 0 && gasBudget > gasCap {
+		gasBudget = gasCap
+	}
+	if args.Coinbase != nil {
+		header.Coinbase = *args.Coinbase
+	}
+	if args.BaseFee != nil {
+		header.BaseFee = args.BaseFee.ToInt()
+	} else if config.IsLondon(header.Number) {
+		header.BaseFee = eip1559.CalcBaseFee(config, parent, header.Time)
+	}
+	if config.IsCancun(header.Number, header.Time) {
+		var excessBlobGas uint64
+		if parent.ExcessBlobGas != nil && parent.BlobGasUsed != nil {
+			excessBlobGas = eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		}
+		header.ExcessBlobGas = &excessBlobGas
+		header.BlobGasUsed = new(uint64)
+	}
+	// Setup context so it may be cancelled when the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var (
+		timeout = s.b.RPCEVMTimeout()
+		cancel  context.CancelFunc
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var (
+		signer       = types.MakeSigner(config, header.Number, header.Time)
+		blockCtx     = core.NewEVMBlockContext(header, NewChainContext(ctx, s.b), &header.Coinbase, config, state)
+		gp           = new(core.GasPool).AddGas(gasBudget)
+		coinbaseDiff = new(big.Int)
+		gasFees      = new(big.Int)
+		bundleHash   = make([]byte, 0, len(txs)*common.HashLength)
+		ret          = &CallBundleResult{StateBlockNumber: parent.Number.Uint64()}
+	)
+	for i, tx := range txs {
+		// Check if the context was cancelled (e.g. timeout)
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d [%v]: %w", i, tx.Hash(), err)
+		}
+		coinbaseBefore := state.GetBalance(header.Coinbase).ToBig()
+
+		state.SetTxContext(tx.Hash(), i)
+		evm := s.b.GetEVM(ctx, msg, state, header, &vm.Config{}, &blockCtx)
+
+		// Wait for the context to be done and cancel the evm. Even if the
+		// EVM has finished, cancelling may be done (repeatedly)
+		go func() {
+			<-ctx.Done()
+			evm.Cancel()
+		}()
+		result, err := core.ApplyMessage(evm, msg, gp)
+		if err := state.Error(); err != nil {
+			return nil, err
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tx %d [%v]: %w", i, tx.Hash(), err)
+		}
+		state.Finalise(config.IsEIP158(header.Number))
+
+		tip, err := tx.EffectiveGasTip(header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d [%v]: %w", i, tx.Hash(), err)
+		}
+		var (
+			txGasFees      = new(big.Int).Mul(new(big.Int).SetUint64(result.UsedGas), tip)
+			txCoinbaseDiff = new(big.Int).Sub(state.GetBalance(header.Coinbase).ToBig(), coinbaseBefore)
+			txResult       = CallBundleTxResult{
+				TxHash:            tx.Hash(),
+				GasUsed:           result.UsedGas,
+				FromAddress:       msg.From,
+				ToAddress:         tx.To(),
+				GasPrice:          new(big.Int).Div(txCoinbaseDiff, new(big.Int).SetUint64(result.UsedGas)).String(),
+				GasFees:           txGasFees.String(),
+				CoinbaseDiff:      txCoinbaseDiff.String(),
+				EthSentToCoinbase: new(big.Int).Sub(txCoinbaseDiff, txGasFees).String(),
+			}
+		)
+		if result.Err != nil {
+			txResult.Error = result.Err.Error()
+			if len(result.Revert()) > 0 {
+				txResult.Revert = newRevertError(result.Revert()).Error()
+			}
+		} else {
+			value := hexutil.Bytes(result.Return())
+			txResult.Value = &value
+		}
+		ret.Results = append(ret.Results, txResult)
+		ret.TotalGasUsed += result.UsedGas
+		coinbaseDiff.Add(coinbaseDiff, txCoinbaseDiff)
+		gasFees.Add(gasFees, txGasFees)
+		bundleHash = append(bundleHash, tx.Hash().Bytes()...)
+	}
+	bundleGasPrice := new(big.Int)
+	if ret.TotalGasUsed > 0 {
+		bundleGasPrice.Div(coinbaseDiff, new(big.Int).SetUint64(ret.TotalGasUsed))
+	}
+	ret.BundleHash = crypto.Keccak256Hash(bundleHash)
+	ret.BundleGasPrice = bundleGasPrice.String()
+	ret.CoinbaseDiff = coinbaseDiff.String()
+	ret.EthSentToCoinbase = new(big.Int).Sub(coinbaseDiff, gasFees).String()
+	ret.GasFees = gasFees.String()
+	return ret, nil
+}